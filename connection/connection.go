@@ -1,16 +1,16 @@
 package connection
 
 import (
+	"context"
 	"database/sql"
+	"embed"
 	"errors"
 	"log"
-	"os"
 	"reflect"
 	"sync"
-	"time"
 
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 var (
@@ -26,6 +26,32 @@ type (
 		AutoMigrateOrWarn(tables ...interface{})
 		Dialect() string
 		ConnString() string
+
+		// RunMigrations applies pending numbered .up.sql files under dir in files,
+		// recording progress in the schema_migrations table.
+		RunMigrations(files embed.FS, dir string) error
+		// Rollback reverts the most recently applied migration using its paired
+		// .down.sql file under dir in files.
+		Rollback(files embed.FS, dir string) error
+		// MigrationStatus returns every applied migration version in ascending order.
+		MigrationStatus() ([]MigrationRecord, error)
+		// ForceVersion sets the recorded schema version without running migration steps,
+		// used to recover from a schema left dirty by a failed deploy.
+		ForceVersion(version int, dirty bool) error
+
+		// Close releases the underlying *sql.DB, stopping any health-check goroutine first.
+		Close() error
+		// Ping checks that the underlying connection is reachable.
+		Ping(ctx context.Context) error
+		// Stats returns the underlying connection pool's statistics.
+		Stats() (sql.DBStats, error)
+
+		// UseReader scopes the returned *gorm.DB to a replica, per the configured policy.
+		UseReader() *gorm.DB
+		// UseWriter scopes the returned *gorm.DB to the primary connection.
+		UseWriter() *gorm.DB
+		// WithSources scopes the returned *gorm.DB to the named dbresolver source group.
+		WithSources(name string) *gorm.DB
 	}
 
 	// connectionManager implements Manager interface
@@ -36,6 +62,11 @@ type (
 		db             *gorm.DB
 		once           sync.Once
 		migratedTables map[reflect.Type]bool
+		options        Options
+		dbMu           sync.RWMutex
+		healthStop     chan struct{}
+		healthDone     chan struct{}
+		closeOnce      sync.Once
 	}
 )
 
@@ -48,24 +79,31 @@ func MustNewBaseConnectionManager(connStr string, dialector gorm.Dialector, conf
 }
 
 func NewBaseConnectionManager(connStr string, dialector gorm.Dialector, config *gorm.Config) (Manager, error) {
+	return NewBaseConnectionManagerWithOptions(connStr, dialector, config)
+}
+
+// NewBaseConnectionManagerWithOptions is NewBaseConnectionManager with functional options
+// to tune the GORM logger (slow-query threshold, log level, log writer) without having to
+// hand-build a *gorm.Config. The logger options are only applied when config is nil; an
+// explicit config is used as-is, matching NewBaseConnectionManager's existing behavior.
+// Pool, health-check and replica options are applied whether or not config is nil.
+func NewBaseConnectionManagerWithOptions(connStr string, dialector gorm.Dialector, config *gorm.Config, opts ...Option) (Manager, error) {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	connMan := &connectionManager{
 		dialector:      dialector,
 		config:         config,
 		once:           sync.Once{},
 		migratedTables: make(map[reflect.Type]bool),
 		connStr:        connStr,
+		options:        options,
 	}
 
 	if connMan.config == nil {
-		defaultLogger := logger.New(
-			log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
-			logger.Config{
-				SlowThreshold: time.Second,  // Slow SQL threshold
-				LogLevel:      logger.Error, // Log level
-				Colorful:      true,         // Disable color
-			},
-		)
-		connMan.config = &gorm.Config{Logger: defaultLogger}
+		connMan.config = &gorm.Config{Logger: options.buildLogger()}
 	}
 
 	// open database connection
@@ -81,29 +119,87 @@ func (c *connectionManager) GetConnection() (*gorm.DB, error) {
 	// this func should be once executed and only once,
 	// even if GetConnection() is called multiple times
 	execOnceOnlyFunc := func() {
-		c.db, err = gorm.Open(c.dialector, c.config)
+		var db *gorm.DB
+		db, err = c.openConnection()
 		if err != nil {
 			return
 		}
+		c.setDB(db)
 
-		var sqlDB *sql.DB
-		sqlDB, err = c.db.DB()
-		if err != nil {
-			return
+		if c.options.HealthCheckInterval > 0 {
+			c.startHealthCheck()
 		}
-		sqlDB.SetMaxIdleConns(10)
-		sqlDB.SetMaxOpenConns(-1)
 	}
 
 	// ensure execOnceOnlyFunc() is only ever executed once
 	c.once.Do(execOnceOnlyFunc)
 
-	return c.db, err
+	return c.getDB(), err
+}
+
+// openConnection opens a fresh *gorm.DB via c.dialector/c.config and applies every
+// per-open setup step (pool sizing, replica routing). Shared between the initial
+// GetConnection open and the health-check goroutine's reconnect, so a reconnect
+// never silently drops setup the initial open performed.
+//
+// gorm.Open stores the *gorm.Config it's given on the resulting *gorm.DB and lazily
+// initializes its Plugins map on that same pointer, so passing c.config through as-is
+// on a second open would hand registerReplicas a Plugins map that already has the
+// dbresolver plugin registered from the first open, and db.Use would fail with
+// "already registered" on every reconnect. A shallow copy with Plugins reset keeps
+// each open's plugin registration independent.
+func (c *connectionManager) openConnection() (*gorm.DB, error) {
+	config := *c.config
+	config.Plugins = nil
+
+	db, err := gorm.Open(c.dialector, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	c.configurePool(sqlDB)
+
+	if len(c.options.Replicas) > 0 {
+		if err := c.registerReplicas(db); err != nil {
+			_ = sqlDB.Close()
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+// getDB returns the current *gorm.DB, safe for concurrent use with the health-check
+// goroutine reopening the connection in the background.
+func (c *connectionManager) getDB() *gorm.DB {
+	c.dbMu.RLock()
+	defer c.dbMu.RUnlock()
+	return c.db
+}
+
+// setDB replaces the current *gorm.DB, safe for concurrent use with GetConnection.
+func (c *connectionManager) setDB(db *gorm.DB) {
+	c.dbMu.Lock()
+	defer c.dbMu.Unlock()
+	c.db = db
+}
+
+// configurePool applies the pool-sizing options to the underlying *sql.DB.
+func (c *connectionManager) configurePool(sqlDB *sql.DB) {
+	sqlDB.SetMaxIdleConns(c.options.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(c.options.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(c.options.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(c.options.ConnMaxIdleTime)
 }
 
 // AutoMigrate create/change database table definition based on the given models
 func (c *connectionManager) AutoMigrate(tables ...interface{}) error {
-	if c.db == nil {
+	db := c.getDB()
+	if db == nil {
 		return ErrConnectionClosed
 	}
 
@@ -118,7 +214,12 @@ func (c *connectionManager) AutoMigrate(tables ...interface{}) error {
 		}
 	}
 
-	return c.db.AutoMigrate(unmigratedTables...)
+	// always target the primary, even when dbresolver is routing other reads to replicas
+	if len(c.options.Replicas) > 0 {
+		db = db.Clauses(dbresolver.Write)
+	}
+
+	return db.AutoMigrate(unmigratedTables...)
 }
 
 // AutoMigrateOrWarn same as AutoMigrate but prints a log instead of returning an error
@@ -130,7 +231,7 @@ func (c *connectionManager) AutoMigrateOrWarn(tables ...interface{}) {
 
 // Dialect return the current database dialect
 func (c *connectionManager) Dialect() string {
-	return c.config.Name()
+	return c.dialector.Name()
 }
 
 // ConnString return the connection string for the current database
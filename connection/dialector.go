@@ -0,0 +1,71 @@
+package connection
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+)
+
+// DialectorFactory builds a gorm.Dialector from the DSN portion of a connection URL
+// (the part after "<scheme>://").
+type DialectorFactory func(dsn string) gorm.Dialector
+
+var (
+	dialectorRegistryMu sync.RWMutex
+	dialectorRegistry   = map[string]DialectorFactory{
+		"postgres":   func(dsn string) gorm.Dialector { return postgres.Open("postgres://" + dsn) },
+		"postgresql": func(dsn string) gorm.Dialector { return postgres.Open("postgres://" + dsn) },
+		"mysql":      func(dsn string) gorm.Dialector { return mysql.Open(dsn) },
+		"sqlite":     func(dsn string) gorm.Dialector { return sqlite.Open(dsn) },
+		"sqlserver":  func(dsn string) gorm.Dialector { return sqlserver.Open("sqlserver://" + dsn) },
+	}
+)
+
+// RegisterDialector registers a DialectorFactory for a URL scheme, letting downstream
+// users plug in additional drivers (e.g. clickhouse, spanner) without modifying this
+// module. Registering an existing scheme overwrites its factory.
+func RegisterDialector(scheme string, factory DialectorFactory) {
+	dialectorRegistryMu.Lock()
+	defer dialectorRegistryMu.Unlock()
+	dialectorRegistry[scheme] = factory
+}
+
+// OpenFromURL parses rawURL's scheme to pick a registered DialectorFactory and builds a
+// Manager from the result via NewBaseConnectionManagerWithOptions. This removes the
+// requirement that every caller import a driver package and hand-construct a
+// gorm.Dialector, the single biggest friction point when a project needs to support
+// more than one backend.
+func OpenFromURL(rawURL string, config *gorm.Config, opts ...Option) (Manager, error) {
+	scheme, dsn, err := splitSchemeDSN(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dialectorRegistryMu.RLock()
+	factory, ok := dialectorRegistry[scheme]
+	dialectorRegistryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("db: no dialector registered for scheme %q", scheme)
+	}
+
+	return NewBaseConnectionManagerWithOptions(rawURL, factory(dsn), config, opts...)
+}
+
+func splitSchemeDSN(rawURL string) (scheme, dsn string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme == "" {
+		return "", "", fmt.Errorf("db: connection URL %q is missing a scheme", rawURL)
+	}
+
+	return u.Scheme, strings.TrimPrefix(rawURL, u.Scheme+"://"), nil
+}
@@ -0,0 +1,61 @@
+package connection
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestOpenFromURL_SchemeDispatch exercises the scheme -> DialectorFactory lookup
+// without requiring a real postgres/mysql/sqlserver server: sqlite is registered
+// under a throwaway scheme so the dispatch path runs against a dialector that can
+// actually open in-process.
+func TestOpenFromURL_SchemeDispatch(t *testing.T) {
+	RegisterDialector("sqlitetest", func(dsn string) gorm.Dialector {
+		return sqlite.Open(dsn)
+	})
+
+	mgr, err := OpenFromURL("sqlitetest://:memory:", nil)
+	if err != nil {
+		t.Fatalf("OpenFromURL: %v", err)
+	}
+	defer mgr.Close()
+
+	if mgr.Dialect() != "sqlite" {
+		t.Fatalf("expected sqlite dialect, got %q", mgr.Dialect())
+	}
+}
+
+// TestOpenFromURL_UnknownScheme confirms an unregistered scheme fails fast with a
+// descriptive error rather than panicking or falling through to some default driver.
+func TestOpenFromURL_UnknownScheme(t *testing.T) {
+	_, err := OpenFromURL("nosuchscheme://host/db", nil)
+	if err == nil {
+		t.Fatal("expected an error for an unregistered scheme")
+	}
+}
+
+// TestRegisterDialector_OverridesExistingScheme confirms RegisterDialector replaces
+// an already-registered factory rather than erroring or being ignored, matching its
+// documented "overwrites its factory" behavior.
+func TestRegisterDialector_OverridesExistingScheme(t *testing.T) {
+	var calls int
+	RegisterDialector("sqlite", func(dsn string) gorm.Dialector {
+		calls++
+		return sqlite.Open(dsn)
+	})
+	t.Cleanup(func() {
+		RegisterDialector("sqlite", func(dsn string) gorm.Dialector { return sqlite.Open(dsn) })
+	})
+
+	mgr, err := OpenFromURL("sqlite://:memory:", nil)
+	if err != nil {
+		t.Fatalf("OpenFromURL: %v", err)
+	}
+	defer mgr.Close()
+
+	if calls != 1 {
+		t.Fatalf("expected overridden factory to be called once, got %d", calls)
+	}
+}
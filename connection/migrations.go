@@ -0,0 +1,317 @@
+package connection
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrDirtyMigration returned when the last migration run failed partway through and
+	// left the schema in an indeterminate state. Run ForceVersion to recover.
+	ErrDirtyMigration = errors.New("db: schema is dirty, run ForceVersion to recover")
+	// ErrMigrationsLocked returned when another runner already holds the migration lock
+	ErrMigrationsLocked = errors.New("db: migrations are locked by another runner")
+
+	migrationFileRe = regexp.MustCompile(`^(\d+)_([^/]*)\.(up|down)\.sql$`)
+)
+
+// MigrationRecord describes the state of a single applied migration version.
+type MigrationRecord struct {
+	Version   int
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+type migrationStep struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// schemaMigration is the GORM model backing the schema_migrations table. Routing
+// reads/writes through GORM (rather than hand-written SQL) means placeholder and
+// upsert syntax are translated per-dialect instead of assuming one driver.
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey"`
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// schemaMigrationsLock is the single-row lock table guarding concurrent migration runs.
+type schemaMigrationsLock struct {
+	ID     int `gorm:"primaryKey"`
+	Locked bool
+}
+
+func (schemaMigrationsLock) TableName() string { return "schema_migrations_lock" }
+
+// RunMigrations applies every pending numbered `.up.sql` file found under dir in files,
+// in ascending version order, recording progress in the schema_migrations table. Each
+// step runs in its own transaction; if a step fails the schema is marked dirty and
+// RunMigrations returns the error without attempting later steps.
+func (c *connectionManager) RunMigrations(files embed.FS, dir string) error {
+	db, err := c.GetConnection()
+	if err != nil {
+		return err
+	}
+
+	if err := ensureMigrationsTables(db); err != nil {
+		return err
+	}
+
+	unlock, err := acquireMigrationLock(db)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, dirty, err := currentMigrationVersion(db)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return ErrDirtyMigration
+	}
+
+	steps, err := loadMigrationSteps(files, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		if step.version <= current {
+			continue
+		}
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(step.up).Error; err != nil {
+				return err
+			}
+			return recordMigrationVersion(tx, step.version)
+		}); err != nil {
+			_ = setMigrationVersion(db, step.version, true)
+			return fmt.Errorf("db: migration %d (%s) failed: %w", step.version, step.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Rollback reverts the most recently applied migration using its paired .down.sql file.
+func (c *connectionManager) Rollback(files embed.FS, dir string) error {
+	db, err := c.GetConnection()
+	if err != nil {
+		return err
+	}
+
+	if err := ensureMigrationsTables(db); err != nil {
+		return err
+	}
+
+	unlock, err := acquireMigrationLock(db)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, dirty, err := currentMigrationVersion(db)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return ErrDirtyMigration
+	}
+	if current == 0 {
+		return nil
+	}
+
+	steps, err := loadMigrationSteps(files, dir)
+	if err != nil {
+		return err
+	}
+
+	var step *migrationStep
+	for i := range steps {
+		if steps[i].version == current {
+			step = &steps[i]
+			break
+		}
+	}
+	if step == nil {
+		return fmt.Errorf("db: no migration step found for version %d", current)
+	}
+	if step.down == "" {
+		return fmt.Errorf("db: migration %d (%s) has no down script", step.version, step.name)
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(step.down).Error; err != nil {
+			return err
+		}
+		return tx.Where("version = ?", step.version).Delete(&schemaMigration{}).Error
+	}); err != nil {
+		_ = setMigrationVersion(db, step.version, true)
+		return fmt.Errorf("db: rollback of migration %d (%s) failed: %w", step.version, step.name, err)
+	}
+
+	return nil
+}
+
+// MigrationStatus returns every applied migration version in ascending order.
+func (c *connectionManager) MigrationStatus() ([]MigrationRecord, error) {
+	db, err := c.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureMigrationsTables(db); err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := db.Order("version ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]MigrationRecord, len(rows))
+	for i, row := range rows {
+		records[i] = MigrationRecord{Version: row.Version, Dirty: row.Dirty, AppliedAt: row.AppliedAt}
+	}
+
+	return records, nil
+}
+
+// ForceVersion sets the recorded schema version without running any migration steps.
+// It exists to recover from a failed deploy that left the schema dirty: operators fix
+// the schema by hand, then call ForceVersion(v, false) to unblock future RunMigrations calls.
+func (c *connectionManager) ForceVersion(version int, dirty bool) error {
+	db, err := c.GetConnection()
+	if err != nil {
+		return err
+	}
+
+	if err := ensureMigrationsTables(db); err != nil {
+		return err
+	}
+
+	// drop records above version so it becomes the recorded (highest) version
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("version > ?", version).Delete(&schemaMigration{}).Error; err != nil {
+			return err
+		}
+		return setMigrationVersion(tx, version, dirty)
+	})
+}
+
+func ensureMigrationsTables(db *gorm.DB) error {
+	if err := db.AutoMigrate(&schemaMigration{}, &schemaMigrationsLock{}); err != nil {
+		return err
+	}
+
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&schemaMigrationsLock{ID: 1, Locked: false}).Error
+}
+
+// acquireMigrationLock claims the single schema_migrations_lock row so only one runner
+// applies migrations at a time. The returned func releases the lock and must be deferred.
+func acquireMigrationLock(db *gorm.DB) (func(), error) {
+	res := db.Model(&schemaMigrationsLock{}).Where("id = ? AND locked = ?", 1, false).Update("locked", true)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrMigrationsLocked
+	}
+
+	return func() {
+		db.Model(&schemaMigrationsLock{}).Where("id = ?", 1).Update("locked", false)
+	}, nil
+}
+
+func currentMigrationVersion(db *gorm.DB) (version int, dirty bool, err error) {
+	var rec schemaMigration
+	err = db.Order("version DESC").First(&rec).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	return rec.Version, rec.Dirty, err
+}
+
+func recordMigrationVersion(tx *gorm.DB, version int) error {
+	return setMigrationVersion(tx, version, false)
+}
+
+// setMigrationVersion upserts the schema_migrations row for version, so marking a
+// version dirty and later clearing it via ForceVersion (or reprocessing the same
+// version) updates the existing row instead of colliding with its primary key.
+func setMigrationVersion(db *gorm.DB, version int, dirty bool) error {
+	rec := schemaMigration{Version: version, Dirty: dirty, AppliedAt: time.Now()}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "version"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dirty", "applied_at"}),
+	}).Create(&rec).Error
+}
+
+// loadMigrationSteps reads every `<version>_<name>.up.sql` / `.down.sql` pair under dir
+// and returns the resulting steps sorted by ascending version.
+func loadMigrationSteps(files embed.FS, dir string) ([]migrationStep, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make(map[int]*migrationStep)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := migrationFileRe.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("db: invalid migration filename %q: %w", entry.Name(), err)
+		}
+		name := matches[2]
+
+		contents, err := fs.ReadFile(files, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		step, ok := steps[version]
+		if !ok {
+			step = &migrationStep{version: version, name: name}
+			steps[version] = step
+		}
+
+		switch matches[3] {
+		case "up":
+			step.up = string(contents)
+		case "down":
+			step.down = string(contents)
+		}
+	}
+
+	ordered := make([]migrationStep, 0, len(steps))
+	for _, step := range steps {
+		ordered = append(ordered, *step)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].version < ordered[j].version })
+
+	return ordered, nil
+}
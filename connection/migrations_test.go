@@ -0,0 +1,92 @@
+package connection
+
+import (
+	"embed"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+)
+
+//go:embed testdata/migrations_broken
+var brokenMigrations embed.FS
+
+//go:embed testdata/migrations_ok
+var okMigrations embed.FS
+
+func newTestManager(t *testing.T) *connectionManager {
+	t.Helper()
+
+	mgr, err := NewBaseConnectionManager(":memory:", sqlite.Open(":memory:"), nil)
+	if err != nil {
+		t.Fatalf("failed to open test connection: %v", err)
+	}
+
+	cm, ok := mgr.(*connectionManager)
+	if !ok {
+		t.Fatalf("expected *connectionManager, got %T", mgr)
+	}
+	return cm
+}
+
+// TestRunMigrations_FailThenForceVersionRecovers reproduces the deploy-failed
+// recovery flow the request called out: a step fails and is marked dirty, then an
+// operator calls ForceVersion to clear it. Before the upsert fix this failed with
+// a UNIQUE constraint violation on schema_migrations.version.
+func TestRunMigrations_FailThenForceVersionRecovers(t *testing.T) {
+	cm := newTestManager(t)
+
+	if err := cm.RunMigrations(brokenMigrations, "testdata/migrations_broken"); err == nil {
+		t.Fatal("expected RunMigrations to fail on the broken step")
+	}
+
+	status, err := cm.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	if len(status) != 1 || status[0].Version != 1 || !status[0].Dirty {
+		t.Fatalf("expected one dirty record for version 1, got %+v", status)
+	}
+
+	if err := cm.ForceVersion(1, false); err != nil {
+		t.Fatalf("ForceVersion should clear the dirty flag, got: %v", err)
+	}
+
+	status, err = cm.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	if len(status) != 1 || status[0].Dirty {
+		t.Fatalf("expected dirty flag cleared, got %+v", status)
+	}
+}
+
+func TestRunMigrations_ApplyThenRollback(t *testing.T) {
+	cm := newTestManager(t)
+
+	if err := cm.RunMigrations(okMigrations, "testdata/migrations_ok"); err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+
+	db, err := cm.GetConnection()
+	if err != nil {
+		t.Fatalf("GetConnection: %v", err)
+	}
+	if !db.Migrator().HasTable("widgets") {
+		t.Fatal("expected widgets table to exist after migrating")
+	}
+
+	if err := cm.Rollback(okMigrations, "testdata/migrations_ok"); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if db.Migrator().HasTable("widgets") {
+		t.Fatal("expected widgets table to be dropped after rollback")
+	}
+
+	status, err := cm.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	if len(status) != 0 {
+		t.Fatalf("expected no recorded migrations after rollback, got %+v", status)
+	}
+}
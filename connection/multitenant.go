@@ -0,0 +1,264 @@
+package connection
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoTenantInContext returned by MultiTenantManager.ForTenant when ctx carries no
+// tenant ID set via WithTenant.
+var ErrNoTenantInContext = errors.New("db: no tenant id in context")
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx carrying tenantID, for use with ForTenant.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ID stored on ctx by WithTenant, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantContextKey{}).(string)
+	return id, ok
+}
+
+// TenantDialectorFunc builds the dialector and connection string for a tenant ID,
+// e.g. substituting it into a database-per-tenant or file-per-tenant DSN.
+type TenantDialectorFunc func(tenantID string) (dialector gorm.Dialector, connStr string)
+
+// tenantConn tracks one tenant's Manager plus how many ForTenant callers are
+// currently using it. refs and evicted are only ever read/written under
+// MultiTenantManager.mu, never tenantConn's own lock, so that deciding to close
+// a connection and removing it from the registry happen atomically.
+type tenantConn struct {
+	once sync.Once
+	mgr  Manager
+	err  error
+
+	refs    int
+	evicted bool
+}
+
+// MultiTenantManager lazily opens one Manager per tenant on first use, evicting the
+// least recently used tenant connection once MaxOpenTenants is exceeded.
+type MultiTenantManager struct {
+	dialectorFn    TenantDialectorFunc
+	config         *gorm.Config
+	opts           []Option
+	maxOpenTenants int
+
+	// mu guards tenants, lru and lruElems together, so a tenantConn's refcount,
+	// evicted flag, and its presence in the registry never go out of sync: the
+	// only way to delete an entry is while holding mu and observing refs <= 0,
+	// which rules out a concurrent acquire() re-incrementing refs on a tenantConn
+	// that's about to be (or just was) closed.
+	mu       sync.Mutex
+	tenants  map[string]*tenantConn
+	lru      *list.List
+	lruElems map[string]*list.Element
+}
+
+// NewMultiTenantManager builds a MultiTenantManager that opens a connection per
+// tenant via dialectorFn the first time that tenant is used, keeping at most
+// maxOpenTenants open at once (0 means unlimited). config and opts are passed
+// through to NewBaseConnectionManagerWithOptions for every tenant connection.
+func NewMultiTenantManager(dialectorFn TenantDialectorFunc, config *gorm.Config, maxOpenTenants int, opts ...Option) *MultiTenantManager {
+	return &MultiTenantManager{
+		dialectorFn:    dialectorFn,
+		config:         config,
+		opts:           opts,
+		maxOpenTenants: maxOpenTenants,
+		tenants:        make(map[string]*tenantConn),
+		lru:            list.New(),
+		lruElems:       make(map[string]*list.Element),
+	}
+}
+
+// ForTenant returns the *gorm.DB for the tenant ID stored on ctx by WithTenant,
+// opening that tenant's connection lazily on first use. Callers must pair every
+// successful ForTenant call with a deferred ReleaseTenant(ctx), otherwise a
+// concurrent eviction can never confirm the connection is idle and will leak it
+// open rather than risk closing it mid-use.
+func (m *MultiTenantManager) ForTenant(ctx context.Context) (*gorm.DB, error) {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenantInContext
+	}
+
+	tc, err := m.acquire(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := tc.mgr.GetConnection()
+	if err != nil {
+		m.ReleaseTenant(ctx)
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// ReleaseTenant marks the caller done using the connection obtained via a prior
+// ForTenant(ctx) call. If that tenant was evicted while in use, releasing the last
+// reference closes it now.
+func (m *MultiTenantManager) ReleaseTenant(ctx context.Context) {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	m.mu.Lock()
+	tc, ok := m.tenants[tenantID]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	tc.refs--
+	shouldClose := tc.refs <= 0 && tc.evicted
+	if shouldClose {
+		delete(m.tenants, tenantID)
+	}
+	m.mu.Unlock()
+
+	if shouldClose && tc.mgr != nil {
+		_ = tc.mgr.Close()
+	}
+}
+
+// acquire returns the tenantConn for tenantID, opening its Manager exactly once
+// (matching connectionManager's own open-once-via-sync.Once semantics) and
+// recording an active reference that must be matched by ReleaseTenant. If the
+// registry entry was being evicted, acquiring it again clears the evicted flag
+// under the same lock, so no one else can close it out from under this caller.
+func (m *MultiTenantManager) acquire(tenantID string) (*tenantConn, error) {
+	m.mu.Lock()
+	tc, ok := m.tenants[tenantID]
+	if !ok {
+		tc = &tenantConn{}
+		m.tenants[tenantID] = tc
+	}
+	tc.refs++
+	tc.evicted = false
+	m.mu.Unlock()
+
+	tc.once.Do(func() {
+		dialector, connStr := m.dialectorFn(tenantID)
+		tc.mgr, tc.err = NewBaseConnectionManagerWithOptions(connStr, dialector, m.config, m.opts...)
+	})
+	if tc.err != nil {
+		m.mu.Lock()
+		tc.refs--
+		// The failed open is cached on tc via sync.Once, so leaving tc registered
+		// would make every future ForTenant(tenantID) replay this error forever.
+		// Drop it so the next acquire starts over with a fresh tenantConn.
+		if cur, ok := m.tenants[tenantID]; ok && cur == tc {
+			delete(m.tenants, tenantID)
+		}
+		m.mu.Unlock()
+		return nil, tc.err
+	}
+
+	m.touch(tenantID)
+
+	return tc, nil
+}
+
+// touch marks tenantID as most-recently-used, then evicts whichever tenants this
+// pushes past maxOpenTenants. Evicting a tenantConn still in use only flags it;
+// the actual Close() call happens outside mu so a slow close doesn't serialize
+// unrelated tenants' acquire/release calls.
+func (m *MultiTenantManager) touch(tenantID string) {
+	if m.maxOpenTenants <= 0 {
+		return
+	}
+
+	var toClose []*tenantConn
+
+	m.mu.Lock()
+	if elem, ok := m.lruElems[tenantID]; ok {
+		m.lru.MoveToFront(elem)
+	} else {
+		m.lruElems[tenantID] = m.lru.PushFront(tenantID)
+	}
+
+	for m.lru.Len() > m.maxOpenTenants {
+		oldest := m.lru.Back()
+		if oldest == nil {
+			break
+		}
+		evictedID := oldest.Value.(string)
+		m.lru.Remove(oldest)
+		delete(m.lruElems, evictedID)
+
+		tc, ok := m.tenants[evictedID]
+		if !ok {
+			continue
+		}
+		tc.evicted = true
+		if tc.refs <= 0 {
+			delete(m.tenants, evictedID)
+			toClose = append(toClose, tc)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, tc := range toClose {
+		if tc.mgr != nil {
+			_ = tc.mgr.Close()
+		}
+	}
+}
+
+// MigrateAllTenants fans AutoMigrate out across every tenant connection currently
+// registered, returning the first error encountered. Tenants already evicted and
+// closed are not migrated.
+func (m *MultiTenantManager) MigrateAllTenants(tables ...interface{}) error {
+	m.mu.Lock()
+	snapshot := make(map[string]*tenantConn, len(m.tenants))
+	for id, tc := range m.tenants {
+		snapshot[id] = tc
+	}
+	m.mu.Unlock()
+
+	var firstErr error
+	for id, tc := range snapshot {
+		if tc.err != nil || tc.mgr == nil {
+			continue
+		}
+		if err := tc.mgr.AutoMigrate(tables...); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("db: tenant %s: %w", id, err)
+		}
+	}
+	return firstErr
+}
+
+// Shutdown closes every open tenant connection's underlying *sql.DB handle,
+// regardless of any outstanding ForTenant references.
+func (m *MultiTenantManager) Shutdown() error {
+	m.mu.Lock()
+	snapshot := make([]*tenantConn, 0, len(m.tenants))
+	for _, tc := range m.tenants {
+		snapshot = append(snapshot, tc)
+	}
+	m.tenants = make(map[string]*tenantConn)
+	m.lru.Init()
+	m.lruElems = make(map[string]*list.Element)
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, tc := range snapshot {
+		if tc.mgr == nil {
+			continue
+		}
+		if err := tc.mgr.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,148 @@
+package connection
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+// Options configures the GORM logger, connection pool, health check and read
+// replicas of a connectionManager. Fields that no Option sets keep the same
+// defaults NewBaseConnectionManager has always used; a zero value set through an
+// Option is used as given.
+type Options struct {
+	// SlowQueryThreshold is the duration after which a query is logged as slow.
+	SlowQueryThreshold time.Duration
+	// LogLevel controls GORM's log verbosity.
+	LogLevel logger.LogLevel
+	// IgnoreRecordNotFoundError suppresses gorm.ErrRecordNotFound from logs.
+	IgnoreRecordNotFoundError bool
+	// Writer receives formatted log lines; defaults to a stdout writer so callers
+	// can plug in adapters such as zapgorm2 without forking this module.
+	Writer logger.Writer
+
+	// MaxIdleConns sets the maximum number of idle connections kept in the pool.
+	MaxIdleConns int
+	// MaxOpenConns sets the maximum number of open connections to the database;
+	// -1 (the default) leaves it unlimited, matching the pool's historical behavior.
+	MaxOpenConns int
+	// ConnMaxLifetime sets the maximum amount of time a connection may be reused.
+	// Zero (the default) means connections are never closed due to age.
+	ConnMaxLifetime time.Duration
+	// ConnMaxIdleTime sets the maximum amount of time a connection may sit idle.
+	// Zero (the default) means connections are never closed due to idleness.
+	ConnMaxIdleTime time.Duration
+
+	// HealthCheckInterval, when non-zero, starts a background goroutine that pings
+	// the connection on this interval and, as soon as one ping fails, reopens it,
+	// retrying with exponential backoff until a reopen succeeds. A single failed
+	// ping is enough to trigger the reopen. Zero (the default) disables health checking.
+	HealthCheckInterval time.Duration
+	// HealthCheckMaxBackoff caps the exponential backoff delay between reopen
+	// attempts. Defaults to one minute when HealthCheckInterval is set.
+	HealthCheckMaxBackoff time.Duration
+
+	// Replicas, when non-empty, registers gorm.io/plugin/dbresolver so reads can be
+	// routed away from the primary connection.
+	Replicas []ReplicaConfig
+	// ReplicaPolicy picks which replica serves a given read; defaults to
+	// dbresolver.RandomPolicy{}. dbresolver.RoundRobinPolicy() is the other built-in option.
+	ReplicaPolicy dbresolver.Policy
+}
+
+// ReplicaConfig describes a single read replica: its DSN (kept for introspection/logging)
+// and the dialector used to open it.
+type ReplicaConfig struct {
+	DSN       string
+	Dialector gorm.Dialector
+}
+
+// Option mutates Options; pass zero or more to NewBaseConnectionManagerWithOptions.
+type Option func(*Options)
+
+// WithSlowQueryThreshold sets the duration after which a query is logged as slow.
+func WithSlowQueryThreshold(d time.Duration) Option {
+	return func(o *Options) { o.SlowQueryThreshold = d }
+}
+
+// WithLogLevel sets GORM's log verbosity.
+func WithLogLevel(level logger.LogLevel) Option {
+	return func(o *Options) { o.LogLevel = level }
+}
+
+// WithIgnoreRecordNotFoundError controls whether gorm.ErrRecordNotFound is logged.
+func WithIgnoreRecordNotFoundError(ignore bool) Option {
+	return func(o *Options) { o.IgnoreRecordNotFoundError = ignore }
+}
+
+// WithLogWriter sets the logger.Writer GORM log lines are written to, letting
+// callers route logs through zap/logrus/zerolog adapters instead of stdout.
+func WithLogWriter(w logger.Writer) Option {
+	return func(o *Options) { o.Writer = w }
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections kept in the pool.
+func WithMaxIdleConns(n int) Option {
+	return func(o *Options) { o.MaxIdleConns = n }
+}
+
+// WithMaxOpenConns sets the maximum number of open connections to the database.
+func WithMaxOpenConns(n int) Option {
+	return func(o *Options) { o.MaxOpenConns = n }
+}
+
+// WithConnMaxLifetime sets the maximum amount of time a connection may be reused.
+func WithConnMaxLifetime(d time.Duration) Option {
+	return func(o *Options) { o.ConnMaxLifetime = d }
+}
+
+// WithConnMaxIdleTime sets the maximum amount of time a connection may sit idle.
+func WithConnMaxIdleTime(d time.Duration) Option {
+	return func(o *Options) { o.ConnMaxIdleTime = d }
+}
+
+// WithHealthCheck opts into a background goroutine that pings the connection on
+// interval and, after any single failed ping, reopens it with exponential backoff
+// (capped at maxBackoff) between reopen attempts. A zero maxBackoff defaults to one minute.
+func WithHealthCheck(interval, maxBackoff time.Duration) Option {
+	return func(o *Options) {
+		o.HealthCheckInterval = interval
+		o.HealthCheckMaxBackoff = maxBackoff
+	}
+}
+
+// WithReplicas registers read replicas behind gorm.io/plugin/dbresolver, routing reads
+// away from the primary connection.
+func WithReplicas(replicas ...ReplicaConfig) Option {
+	return func(o *Options) { o.Replicas = replicas }
+}
+
+// WithReplicaPolicy picks which replica serves a given read, e.g. dbresolver.RandomPolicy{}
+// (the default) or dbresolver.RoundRobinPolicy().
+func WithReplicaPolicy(policy dbresolver.Policy) Option {
+	return func(o *Options) { o.ReplicaPolicy = policy }
+}
+
+func defaultOptions() Options {
+	return Options{
+		SlowQueryThreshold: time.Second,
+		LogLevel:           logger.Error,
+		Writer:             log.New(os.Stdout, "\r\n", log.LstdFlags),
+		MaxIdleConns:       10,
+		MaxOpenConns:       -1,
+		ReplicaPolicy:      dbresolver.RandomPolicy{},
+	}
+}
+
+func (o Options) buildLogger() logger.Interface {
+	return logger.New(o.Writer, logger.Config{
+		SlowThreshold:             o.SlowQueryThreshold,
+		LogLevel:                  o.LogLevel,
+		IgnoreRecordNotFoundError: o.IgnoreRecordNotFoundError,
+		Colorful:                  true,
+	})
+}
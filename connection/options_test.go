@@ -0,0 +1,104 @@
+package connection
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+// TestOptions_DefaultsApplyWhenUnset confirms defaultOptions' values survive an
+// empty Option list, matching NewBaseConnectionManager's documented historical
+// pool/logger behavior.
+func TestOptions_DefaultsApplyWhenUnset(t *testing.T) {
+	opts := defaultOptions()
+
+	if opts.MaxIdleConns != 10 {
+		t.Fatalf("expected default MaxIdleConns 10, got %d", opts.MaxIdleConns)
+	}
+	if opts.MaxOpenConns != -1 {
+		t.Fatalf("expected default MaxOpenConns -1, got %d", opts.MaxOpenConns)
+	}
+	if opts.LogLevel != logger.Error {
+		t.Fatalf("expected default LogLevel Error, got %v", opts.LogLevel)
+	}
+	if _, ok := opts.ReplicaPolicy.(dbresolver.RandomPolicy); !ok {
+		t.Fatalf("expected default ReplicaPolicy RandomPolicy, got %T", opts.ReplicaPolicy)
+	}
+}
+
+// TestOptions_FunctionalOptionsOverrideDefaults confirms each With* option listed
+// below sets its own field, applied in order over defaultOptions().
+func TestOptions_FunctionalOptionsOverrideDefaults(t *testing.T) {
+	options := defaultOptions()
+	for _, opt := range []Option{
+		WithSlowQueryThreshold(2 * time.Second),
+		WithLogLevel(logger.Info),
+		WithIgnoreRecordNotFoundError(true),
+		WithMaxIdleConns(5),
+		WithMaxOpenConns(50),
+		WithConnMaxLifetime(time.Hour),
+		WithConnMaxIdleTime(10 * time.Minute),
+		WithHealthCheck(time.Second, time.Minute),
+		WithReplicaPolicy(dbresolver.RoundRobinPolicy()),
+	} {
+		opt(&options)
+	}
+
+	if options.SlowQueryThreshold != 2*time.Second {
+		t.Fatalf("WithSlowQueryThreshold not applied, got %v", options.SlowQueryThreshold)
+	}
+	if options.LogLevel != logger.Info {
+		t.Fatalf("WithLogLevel not applied, got %v", options.LogLevel)
+	}
+	if !options.IgnoreRecordNotFoundError {
+		t.Fatal("WithIgnoreRecordNotFoundError not applied")
+	}
+	if options.MaxIdleConns != 5 {
+		t.Fatalf("WithMaxIdleConns not applied, got %d", options.MaxIdleConns)
+	}
+	if options.MaxOpenConns != 50 {
+		t.Fatalf("WithMaxOpenConns not applied, got %d", options.MaxOpenConns)
+	}
+	if options.ConnMaxLifetime != time.Hour {
+		t.Fatalf("WithConnMaxLifetime not applied, got %v", options.ConnMaxLifetime)
+	}
+	if options.ConnMaxIdleTime != 10*time.Minute {
+		t.Fatalf("WithConnMaxIdleTime not applied, got %v", options.ConnMaxIdleTime)
+	}
+	if options.HealthCheckInterval != time.Second || options.HealthCheckMaxBackoff != time.Minute {
+		t.Fatalf("WithHealthCheck not applied, got interval=%v maxBackoff=%v", options.HealthCheckInterval, options.HealthCheckMaxBackoff)
+	}
+	if _, ok := options.ReplicaPolicy.(dbresolver.RandomPolicy); ok {
+		t.Fatal("WithReplicaPolicy did not override the default RandomPolicy")
+	}
+}
+
+// TestOptions_PoolSettingsAppliedToRealConnection confirms WithMaxOpenConns
+// actually reaches the underlying *sql.DB's pool configuration, not just the
+// Options struct, by opening a real manager and reading sql.DBStats back.
+func TestOptions_PoolSettingsAppliedToRealConnection(t *testing.T) {
+	mgr, err := NewBaseConnectionManagerWithOptions(":memory:", sqlite.Open(":memory:"), nil,
+		WithMaxOpenConns(1),
+		WithMaxIdleConns(1),
+	)
+	if err != nil {
+		t.Fatalf("NewBaseConnectionManagerWithOptions: %v", err)
+	}
+	defer mgr.Close()
+
+	db, err := mgr.GetConnection()
+	if err != nil {
+		t.Fatalf("GetConnection: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db.DB(): %v", err)
+	}
+	stats := sqlDB.Stats()
+	if stats.MaxOpenConnections != 1 {
+		t.Fatalf("expected MaxOpenConnections 1, got %d", stats.MaxOpenConnections)
+	}
+}
@@ -0,0 +1,139 @@
+package connection
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+const defaultHealthCheckMaxBackoff = time.Minute
+
+// Ping checks that the underlying connection is reachable.
+func (c *connectionManager) Ping(ctx context.Context) error {
+	db, err := c.GetConnection()
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Stats returns the underlying connection pool's statistics.
+func (c *connectionManager) Stats() (sql.DBStats, error) {
+	db, err := c.GetConnection()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
+}
+
+// Close stops any running health-check goroutine and closes the underlying *sql.DB.
+// Close is safe to call multiple times.
+func (c *connectionManager) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		if c.healthStop != nil {
+			close(c.healthStop)
+			<-c.healthDone
+		}
+
+		db := c.getDB()
+		if db == nil {
+			return
+		}
+
+		var sqlDB *sql.DB
+		sqlDB, err = db.DB()
+		if err != nil {
+			return
+		}
+		err = sqlDB.Close()
+	})
+	return err
+}
+
+// startHealthCheck pings the connection on options.HealthCheckInterval and, as soon
+// as one ping fails, reopens the underlying *sql.DB with exponential backoff capped
+// at options.HealthCheckMaxBackoff; consecutive failures are not counted before the
+// reopen starts. Must only be called once GetConnection
+// has successfully opened c.db.
+func (c *connectionManager) startHealthCheck() {
+	c.healthStop = make(chan struct{})
+	c.healthDone = make(chan struct{})
+
+	maxBackoff := c.options.HealthCheckMaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultHealthCheckMaxBackoff
+	}
+
+	go func() {
+		defer close(c.healthDone)
+
+		ticker := time.NewTicker(c.options.HealthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.healthStop:
+				return
+			case <-ticker.C:
+				if err := c.pingOnce(); err != nil {
+					c.reconnectWithBackoff(c.healthStop, maxBackoff)
+				}
+			}
+		}
+	}()
+}
+
+func (c *connectionManager) pingOnce() error {
+	sqlDB, err := c.getDB().DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.options.HealthCheckInterval)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+// reconnectWithBackoff retries opening a fresh connection, doubling the delay between
+// attempts up to maxBackoff, until it succeeds or stop is closed.
+func (c *connectionManager) reconnectWithBackoff(stop chan struct{}, maxBackoff time.Duration) {
+	delay := c.options.HealthCheckInterval
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(delay):
+		}
+
+		db, err := c.openConnection()
+		if err == nil {
+			old := c.getDB()
+			c.setDB(db)
+
+			if oldSQLDB, oldErr := old.DB(); oldErr == nil {
+				if closeErr := oldSQLDB.Close(); closeErr != nil {
+					log.Printf("db: failed closing replaced connection pool: %v\n", closeErr)
+				}
+			}
+
+			return
+		}
+
+		log.Printf("db: health check reconnect failed: %v\n", err)
+
+		delay *= 2
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestPing_Stats_Close exercises the basic pool lifecycle: Ping succeeds against
+// a live connection, Stats reflects it's actually open, and Close shuts it down
+// idempotently (calling it twice must not error or panic).
+func TestPing_Stats_Close(t *testing.T) {
+	cm := newTestManager(t)
+
+	if err := cm.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	stats, err := cm.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.OpenConnections < 1 {
+		t.Fatalf("expected at least one open connection, got %+v", stats)
+	}
+
+	if err := cm.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := cm.Close(); err != nil {
+		t.Fatalf("second Close should be a no-op, got: %v", err)
+	}
+}
+
+// failNTimesDialector wraps a real gorm.Dialector so its first n Initialize calls
+// fail, letting a test drive reconnectWithBackoff's retry loop deterministically.
+type failNTimesDialector struct {
+	gorm.Dialector
+	n        int
+	attempts *int
+}
+
+func (d failNTimesDialector) Initialize(db *gorm.DB) error {
+	*d.attempts++
+	if *d.attempts <= d.n {
+		return errors.New("simulated open failure")
+	}
+	return d.Dialector.Initialize(db)
+}
+
+// TestHealthCheck_ReconnectsAfterFailure drives reconnectWithBackoff directly
+// against a dialector that fails its first open attempt and succeeds on the
+// second, confirming it retries (rather than giving up after one failure) and
+// swaps in the new *gorm.DB via setDB.
+func TestHealthCheck_ReconnectsAfterFailure(t *testing.T) {
+	cm := newTestManager(t)
+
+	var attempts int
+	cm.dialector = failNTimesDialector{Dialector: cm.dialector, n: 1, attempts: &attempts}
+
+	stop := make(chan struct{})
+	cm.reconnectWithBackoff(stop, time.Millisecond)
+
+	if attempts < 2 {
+		t.Fatalf("expected at least 2 open attempts (1 failure + 1 success), got %d", attempts)
+	}
+
+	if err := cm.Ping(context.Background()); err != nil {
+		t.Fatalf("expected the reconnected db to be usable, got: %v", err)
+	}
+}
+
+// TestHealthCheck_ReconnectRegistersReplicas confirms reconnectWithBackoff routes
+// through openConnection (not a bare gorm.Open), so a reconnect re-registers
+// replicas instead of silently dropping dbresolver.
+func TestHealthCheck_ReconnectRegistersReplicas(t *testing.T) {
+	primaryDSN := "file:pooltest_primary?mode=memory&cache=shared"
+	replicaDSN := "file:pooltest_replica?mode=memory&cache=shared"
+	seedProbeTable(t, primaryDSN, "primary")
+	seedProbeTable(t, replicaDSN, "replica")
+
+	mgr, err := NewBaseConnectionManagerWithOptions(primaryDSN, sqlite.Open(primaryDSN), nil,
+		WithReplicas(ReplicaConfig{DSN: replicaDSN, Dialector: sqlite.Open(replicaDSN)}),
+	)
+	if err != nil {
+		t.Fatalf("NewBaseConnectionManagerWithOptions: %v", err)
+	}
+	defer mgr.Close()
+
+	cm := mgr.(*connectionManager)
+
+	stop := make(chan struct{})
+	cm.reconnectWithBackoff(stop, time.Millisecond)
+
+	var readerValue string
+	if err := cm.UseReader().Raw("SELECT value FROM probe").Scan(&readerValue).Error; err != nil {
+		t.Fatalf("UseReader query after reconnect: %v", err)
+	}
+	if readerValue != "replica" {
+		t.Fatalf("expected reconnect to preserve replica routing, got value %q", readerValue)
+	}
+}
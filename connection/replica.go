@@ -0,0 +1,40 @@
+package connection
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// registerReplicas wires gorm.io/plugin/dbresolver into db, routing reads across
+// c.options.Replicas under c.options.ReplicaPolicy while keeping db itself (the
+// primary) as the sole write target. Must run after gorm.Open and before any
+// migration, so AutoMigrate always lands on the primary.
+func (c *connectionManager) registerReplicas(db *gorm.DB) error {
+	replicas := make([]gorm.Dialector, len(c.options.Replicas))
+	for i, replica := range c.options.Replicas {
+		replicas[i] = replica.Dialector
+	}
+
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   c.options.ReplicaPolicy,
+	}))
+}
+
+// UseReader scopes the returned *gorm.DB to a replica, per the configured policy.
+// With no replicas configured this is equivalent to the primary connection.
+func (c *connectionManager) UseReader() *gorm.DB {
+	return c.getDB().Clauses(dbresolver.Read)
+}
+
+// UseWriter scopes the returned *gorm.DB to the primary connection.
+func (c *connectionManager) UseWriter() *gorm.DB {
+	return c.getDB().Clauses(dbresolver.Write)
+}
+
+// WithSources scopes the returned *gorm.DB to the named dbresolver source group,
+// registered separately via Manager's underlying dbresolver config. dbresolver.Use
+// only ever selects one group per query, so only a single name is accepted.
+func (c *connectionManager) WithSources(name string) *gorm.DB {
+	return c.getDB().Clauses(dbresolver.Use(name))
+}
@@ -0,0 +1,68 @@
+package connection
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// seedProbeTable opens its own direct connection to dsn (bypassing any
+// connectionManager) and creates a one-row "probe" table holding value, so a
+// later read through the manager can tell which physical database served it.
+func seedProbeTable(t *testing.T, dsn, value string) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("seedProbeTable(%s): open: %v", dsn, err)
+	}
+	if err := db.Exec("CREATE TABLE probe (value text)").Error; err != nil {
+		t.Fatalf("seedProbeTable(%s): create table: %v", dsn, err)
+	}
+	if err := db.Exec("INSERT INTO probe (value) VALUES (?)", value).Error; err != nil {
+		t.Fatalf("seedProbeTable(%s): insert: %v", dsn, err)
+	}
+}
+
+// TestReplicaRouting_UseReaderAndUseWriterHitDifferentSources sets up a primary
+// and a replica with distinguishable data, then confirms UseWriter always reads
+// back the primary's row and UseReader always reads back the replica's row -
+// proving dbresolver is actually routing reads and writes to different physical
+// connections rather than both ending up on the same one.
+func TestReplicaRouting_UseReaderAndUseWriterHitDifferentSources(t *testing.T) {
+	primaryDSN := "file:replicatest_primary?mode=memory&cache=shared"
+	replicaDSN := "file:replicatest_replica?mode=memory&cache=shared"
+
+	seedProbeTable(t, primaryDSN, "primary")
+	seedProbeTable(t, replicaDSN, "replica")
+
+	mgr, err := NewBaseConnectionManagerWithOptions(primaryDSN, sqlite.Open(primaryDSN), nil,
+		WithReplicas(ReplicaConfig{DSN: replicaDSN, Dialector: sqlite.Open(replicaDSN)}),
+	)
+	if err != nil {
+		t.Fatalf("NewBaseConnectionManagerWithOptions: %v", err)
+	}
+	defer mgr.Close()
+
+	cm, ok := mgr.(*connectionManager)
+	if !ok {
+		t.Fatalf("expected *connectionManager, got %T", mgr)
+	}
+
+	var writerValue string
+	if err := cm.UseWriter().Raw("SELECT value FROM probe").Scan(&writerValue).Error; err != nil {
+		t.Fatalf("UseWriter query: %v", err)
+	}
+	if writerValue != "primary" {
+		t.Fatalf("expected UseWriter to hit the primary, got value %q", writerValue)
+	}
+
+	var readerValue string
+	if err := cm.UseReader().Raw("SELECT value FROM probe").Scan(&readerValue).Error; err != nil {
+		t.Fatalf("UseReader query: %v", err)
+	}
+	if readerValue != "replica" {
+		t.Fatalf("expected UseReader to hit the replica, got value %q", readerValue)
+	}
+}